@@ -1,58 +1,109 @@
 package cache
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
 
 type (
 	// Driver represents a cache driver instance
 	Driver interface {
-		Get(key string) (interface{}, bool)
-		Put(key string, data interface{}, expire int64)
-		Remove(key string)
-		Clear()
+		Get(ctx context.Context, key string) (interface{}, bool, error)
+		Put(ctx context.Context, key string, data interface{}, expire int64) error
+		Remove(ctx context.Context, key string) error
+		Clear(ctx context.Context) error
+		Incr(ctx context.Context, key string, delta int64) (int64, error)
+		Decr(ctx context.Context, key string, delta int64) (int64, error)
 	}
 
 	// Config is the cache instance configuration
 	Config struct {
-		Driver   string
-		MaxSize  int
-		Address  string
-		Username string
-		Password string
-		Use bool
+		Driver     string
+		MaxSize    int
+		Address    string
+		Username   string
+		Password   string
+		PoolSize   int
+		GCInterval int64
+		Use        bool
 	}
 
 	// Cache represents a cache instance
 	Cache struct {
 		config *Config
 		driver Driver
+		group  callGroup
+
+		subsMu sync.Mutex
+		subs   []chan Event
+
+		tagsMu  sync.Mutex
+		tags    map[string]map[string]struct{}
+		keyTags map[string]map[string]struct{}
+	}
+
+	// DriverFactory builds a Driver instance from the cache config,
+	// letting remote drivers dial out using Address/Username/Password/PoolSize
+	DriverFactory func(cfg *Config) Driver
+
+	// Closer is implemented by drivers that run background goroutines
+	// (such as a janitor) needing an explicit shutdown
+	Closer interface {
+		Close() error
+	}
+
+	// EvictionNotifier is implemented by drivers that may drop a key on
+	// their own initiative - size-bounded LRU eviction, lazy or janitor
+	// expiry - rather than through an explicit Remove/Clear call. The
+	// owning Cache registers a callback via OnEvict to keep derived state,
+	// such as the tag reverse index, from growing unboundedly for keys the
+	// driver has already forgotten about
+	EvictionNotifier interface {
+		OnEvict(fn func(key string))
 	}
 )
 
 var (
-	drivers = map[string]Driver{
-		"memory": NewMemoryCache(),
+	drivers = map[string]DriverFactory{
+		"memory": func(cfg *Config) Driver {
+			return NewMemoryCache(time.Duration(cfg.GCInterval) * time.Second)
+		},
 	}
 )
 
 // New returns a cache instance with provided config
 func New(cfg *Config) *Cache {
-	driver, ok := drivers[cfg.Driver]
+	factory, ok := drivers[cfg.Driver]
 	if !ok {
 		errStr := fmt.Sprintf("cache: cache provider %s is not registered", cfg.Driver)
 		panic(errStr)
 	}
 
-	return &Cache{
-		config: cfg,
-		driver: driver,
+	c := &Cache{
+		config:  cfg,
+		driver:  factory(cfg),
+		group:   callGroup{calls: make(map[string]*call)},
+		tags:    make(map[string]map[string]struct{}),
+		keyTags: make(map[string]map[string]struct{}),
 	}
+
+	if notifier, ok := c.driver.(EvictionNotifier); ok {
+		notifier.OnEvict(func(key string) {
+			c.untag(key)
+			c.emit(Event{Op: OpRemove, Key: key})
+		})
+	}
+
+	return c
 }
 
-// RegisterDriver registers a driver
+// RegisterDriver registers a driver factory
 // panics if driver is already registered
-func RegisterDriver(name string, driver Driver) {
-	if driver == nil {
-		panic("cache: driver is nil")
+func RegisterDriver(name string, factory DriverFactory) {
+	if factory == nil {
+		panic("cache: driver factory is nil")
 	}
 
 	if _, ok := drivers[name]; ok {
@@ -60,97 +111,231 @@ func RegisterDriver(name string, driver Driver) {
 		panic(errStr)
 	}
 
-	drivers[name] = driver
+	drivers[name] = factory
 }
 
 // Get fetches an item from session store by key,
 // returns an empty interface and false if it doesnt exist
-func (c *Cache) Get(key string) (interface{}, bool) {
-	return c.driver.Get(key)
+func (c *Cache) Get(ctx context.Context, key string) (interface{}, bool, error) {
+	return c.driver.Get(ctx, key)
 }
 
 // GetString returns a string item from session store
-func (c *Cache) GetString(key string) (string, bool) {
-	data, ok := c.Get(key)
-	if !ok {
-		return "", false
+func (c *Cache) GetString(ctx context.Context, key string) (string, bool, error) {
+	data, ok, err := c.Get(ctx, key)
+	if !ok || err != nil {
+		return "", false, err
 	}
 
 	str, ok := data.(string)
-	return str, ok
+	return str, ok, nil
 }
 
 // GetInt returns an integer item from session store
-func (c *Cache) GetInt(key string) (int, bool) {
-	data, ok := c.Get(key)
-	if !ok {
-		return 0, false
+func (c *Cache) GetInt(ctx context.Context, key string) (int, bool, error) {
+	data, ok, err := c.Get(ctx, key)
+	if !ok || err != nil {
+		return 0, false, err
+	}
+
+	i, ok := data.(int)
+	return i, ok, nil
+}
+
+// GetInt64 returns an integer item from session store as an int64, the type
+// Incr/Decr store their value as. Use this instead of GetInt to read back a
+// counter maintained with Incr/Decr
+func (c *Cache) GetInt64(ctx context.Context, key string) (int64, bool, error) {
+	data, ok, err := c.Get(ctx, key)
+	if !ok || err != nil {
+		return 0, false, err
 	}
 
-	str, ok := data.(int)
-	return str, ok
+	i, ok := data.(int64)
+	return i, ok, nil
 }
 
 // Put adds an item to cache for the specified duration
 // identified by provided key
-func (c *Cache) Put(key string, data interface{}, duration int64) {
-	c.driver.Put(key, data, duration)
+func (c *Cache) Put(ctx context.Context, key string, data interface{}, duration int64) error {
+	if err := c.driver.Put(ctx, key, data, duration); err != nil {
+		return err
+	}
+
+	c.emit(Event{Op: OpPut, Key: key})
+	return nil
 }
 
 // PutForever adds an item to the cache forever
-func (c *Cache) PutForever(key string, data interface{}) {
-	c.driver.Put(key, data, 0)
+func (c *Cache) PutForever(ctx context.Context, key string, data interface{}) error {
+	return c.Put(ctx, key, data, 0)
 }
 
 // Remove deletes an item from session store by provided key
-func (c *Cache) Remove(key string) {
-	c.driver.Remove(key)
+func (c *Cache) Remove(ctx context.Context, key string) error {
+	if err := c.driver.Remove(ctx, key); err != nil {
+		return err
+	}
+
+	c.untag(key)
+	c.emit(Event{Op: OpRemove, Key: key})
+	return nil
 }
 
 // Pull gets an item from session store and deletes the item from session
-func (c *Cache) Pull(key string) (interface{}, bool) {
-	data, ok := c.driver.Get(key)
-	c.driver.Remove(key)
+func (c *Cache) Pull(ctx context.Context, key string) (interface{}, bool, error) {
+	data, ok, err := c.driver.Get(ctx, key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := c.Remove(ctx, key); err != nil {
+		return nil, false, err
+	}
 
-	return data, ok
+	return data, ok, nil
 }
 
 // PullString gets a string item from session store and deletes the item from session
-func (c *Cache) PullString(key string) (string, bool) {
-	data, ok := c.GetString(key)
-	c.driver.Remove(key)
+func (c *Cache) PullString(ctx context.Context, key string) (string, bool, error) {
+	data, ok, err := c.GetString(ctx, key)
+	if err != nil {
+		return "", false, err
+	}
+
+	if err := c.Remove(ctx, key); err != nil {
+		return "", false, err
+	}
 
-	return data, ok
+	return data, ok, nil
 }
 
 // PullInt gets an integer item from session store and deletes the item from session
-func (c *Cache) PullInt(key string) (int, bool) {
-	data, ok := c.GetInt(key)
-	c.driver.Remove(key)
+func (c *Cache) PullInt(ctx context.Context, key string) (int, bool, error) {
+	data, ok, err := c.GetInt(ctx, key)
+	if err != nil {
+		return 0, false, err
+	}
 
-	return data, ok
+	if err := c.Remove(ctx, key); err != nil {
+		return 0, false, err
+	}
+
+	return data, ok, nil
+}
+
+// PullInt64 gets an int64 item from session store and deletes the item from
+// session, the counterpart to GetInt64 for reading back a counter maintained
+// with Incr/Decr
+func (c *Cache) PullInt64(ctx context.Context, key string) (int64, bool, error) {
+	data, ok, err := c.GetInt64(ctx, key)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if err := c.Remove(ctx, key); err != nil {
+		return 0, false, err
+	}
+
+	return data, ok, nil
 }
 
 // Remember fetches an item from the cache, if the item does not exist,
 // passed callback is executed, the data from the callback is stored in the cache
-// for the passed duration and returned to the caller
-func (c *Cache) Remember(key string, duration int64, cb func() interface{}) interface{} {
-	data, ok := c.Get(key)
+// for the passed duration and returned to the caller. Concurrent calls for the
+// same key that miss the cache share a single callback invocation
+func (c *Cache) Remember(ctx context.Context, key string, duration int64, cb func() interface{}) (interface{}, error) {
+	return c.RememberContext(ctx, key, duration, func(ctx context.Context) (interface{}, error) {
+		return cb(), nil
+	})
+}
+
+// RememberForever does the same as Remember except, the data is stored forever
+func (c *Cache) RememberForever(ctx context.Context, key string, cb func() interface{}) (interface{}, error) {
+	return c.Remember(ctx, key, 0, cb)
+}
+
+// RememberContext does the same as Remember except the callback receives ctx
+// and may fail, in which case nothing is cached and the error is returned to
+// every caller waiting on that key
+func (c *Cache) RememberContext(ctx context.Context, key string, duration int64, cb func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	data, ok, err := c.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
 	if ok {
-		return data
+		return data, nil
 	}
 
-	data = cb()
-	c.Put(key, data, duration)
-	return data
-}
+	return c.group.do(key, func() (interface{}, error) {
+		data, ok, err := c.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
 
-// RememberForever does the same as Remember except, the data is stored forever
-func (c *Cache) RememberForever(key string, cb func() interface{}) interface{} {
-	return c.Remember(key, 0, cb)
+		if ok {
+			return data, nil
+		}
+
+		data, err = cb(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := c.Put(ctx, key, data, duration); err != nil {
+			return nil, err
+		}
+
+		return data, nil
+	})
 }
 
 // Clear empties the session store
-func (c *Cache) Clear() {
-	c.driver.Clear()
+func (c *Cache) Clear(ctx context.Context) error {
+	if err := c.driver.Clear(ctx); err != nil {
+		return err
+	}
+
+	c.tagsMu.Lock()
+	c.tags = make(map[string]map[string]struct{})
+	c.keyTags = make(map[string]map[string]struct{})
+	c.tagsMu.Unlock()
+
+	c.emit(Event{Op: OpClear})
+	return nil
+}
+
+// Incr atomically adds delta to the integer stored at key, creating it
+// with a value of 0 first if it doesn't already exist, and returns the result
+func (c *Cache) Incr(ctx context.Context, key string, delta int64) (int64, error) {
+	n, err := c.driver.Incr(ctx, key, delta)
+	if err != nil {
+		return 0, err
+	}
+
+	c.emit(Event{Op: OpPut, Key: key})
+	return n, nil
+}
+
+// Decr atomically subtracts delta from the integer stored at key, creating it
+// with a value of 0 first if it doesn't already exist, and returns the result
+func (c *Cache) Decr(ctx context.Context, key string, delta int64) (int64, error) {
+	n, err := c.driver.Decr(ctx, key, delta)
+	if err != nil {
+		return 0, err
+	}
+
+	c.emit(Event{Op: OpPut, Key: key})
+	return n, nil
+}
+
+// Close releases resources held by the underlying driver, such as a
+// running janitor goroutine. It is a no-op for drivers that don't need it
+func (c *Cache) Close() error {
+	if closer, ok := c.driver.(Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
 }
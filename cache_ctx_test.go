@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/go-redis/redis/v8"
+)
+
+// canceledContext returns a context that is already canceled
+func canceledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
+// TestMemoryCacheCanceledContext ensures every MemoryCache method surfaces
+// a canceled context instead of touching the store
+func TestMemoryCacheCanceledContext(t *testing.T) {
+	m := NewMemoryCache(0)
+	ctx := canceledContext()
+
+	if _, _, err := m.Get(ctx, "k"); err != context.Canceled {
+		t.Fatalf("Get: got %v, want context.Canceled", err)
+	}
+	if err := m.Put(ctx, "k", "v", 0); err != context.Canceled {
+		t.Fatalf("Put: got %v, want context.Canceled", err)
+	}
+	if err := m.Remove(ctx, "k"); err != context.Canceled {
+		t.Fatalf("Remove: got %v, want context.Canceled", err)
+	}
+	if err := m.Clear(ctx); err != context.Canceled {
+		t.Fatalf("Clear: got %v, want context.Canceled", err)
+	}
+	if _, err := m.Incr(ctx, "k", 1); err != context.Canceled {
+		t.Fatalf("Incr: got %v, want context.Canceled", err)
+	}
+}
+
+// TestLRUCacheCanceledContext ensures every LRUCache method surfaces a
+// canceled context instead of touching the store
+func TestLRUCacheCanceledContext(t *testing.T) {
+	l := NewLRUCache(0)
+	ctx := canceledContext()
+
+	if _, _, err := l.Get(ctx, "k"); err != context.Canceled {
+		t.Fatalf("Get: got %v, want context.Canceled", err)
+	}
+	if err := l.Put(ctx, "k", "v", 0); err != context.Canceled {
+		t.Fatalf("Put: got %v, want context.Canceled", err)
+	}
+	if err := l.Remove(ctx, "k"); err != context.Canceled {
+		t.Fatalf("Remove: got %v, want context.Canceled", err)
+	}
+	if err := l.Clear(ctx); err != context.Canceled {
+		t.Fatalf("Clear: got %v, want context.Canceled", err)
+	}
+	if _, err := l.Incr(ctx, "k", 1); err != context.Canceled {
+		t.Fatalf("Incr: got %v, want context.Canceled", err)
+	}
+}
+
+// TestMemcachedCacheCanceledContext ensures every MemcachedCache method
+// checks ctx before ever touching the wire, so a canceled context is
+// reported without needing a live memcached server
+func TestMemcachedCacheCanceledContext(t *testing.T) {
+	m := &MemcachedCache{client: memcache.New("127.0.0.1:1")}
+	ctx := canceledContext()
+
+	if _, _, err := m.Get(ctx, "k"); err != context.Canceled {
+		t.Fatalf("Get: got %v, want context.Canceled", err)
+	}
+	if err := m.Put(ctx, "k", "v", 0); err != context.Canceled {
+		t.Fatalf("Put: got %v, want context.Canceled", err)
+	}
+	if err := m.Remove(ctx, "k"); err != context.Canceled {
+		t.Fatalf("Remove: got %v, want context.Canceled", err)
+	}
+	if err := m.Clear(ctx); err != context.Canceled {
+		t.Fatalf("Clear: got %v, want context.Canceled", err)
+	}
+	if _, err := m.Incr(ctx, "k", 1); err != context.Canceled {
+		t.Fatalf("Incr: got %v, want context.Canceled", err)
+	}
+}
+
+// TestRedisCacheCanceledContext ensures a canceled context surfaces as a
+// DriverError wrapping context.Canceled, without needing a live redis
+// server - the client respects ctx before attempting the round trip
+func TestRedisCacheCanceledContext(t *testing.T) {
+	r := &RedisCache{client: redis.NewClient(&redis.Options{Addr: "127.0.0.1:1"})}
+	ctx := canceledContext()
+
+	_, _, err := r.Get(ctx, "k")
+	if err == nil {
+		t.Fatal("Get: expected an error for a canceled context")
+	}
+	var de *DriverError
+	if !errors.As(err, &de) {
+		t.Fatalf("Get: expected a *DriverError, got %T: %v", err, err)
+	}
+	if de.Unwrap() != context.Canceled {
+		t.Fatalf("Get: expected the wrapped error to be context.Canceled, got %v", de.Unwrap())
+	}
+}
+
+// TestCacheCanceledContextPropagates ensures a canceled context passed to
+// the Cache facade reaches the underlying driver rather than being
+// silently ignored
+func TestCacheCanceledContextPropagates(t *testing.T) {
+	c := New(&Config{Driver: "memory"})
+	ctx := canceledContext()
+
+	if _, _, err := c.Get(ctx, "k"); err != context.Canceled {
+		t.Fatalf("Get: got %v, want context.Canceled", err)
+	}
+	if err := c.Put(ctx, "k", "v", 0); err != context.Canceled {
+		t.Fatalf("Put: got %v, want context.Canceled", err)
+	}
+	if err := c.Remove(ctx, "k"); err != context.Canceled {
+		t.Fatalf("Remove: got %v, want context.Canceled", err)
+	}
+	if err := c.Clear(ctx); err != context.Canceled {
+		t.Fatalf("Clear: got %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,32 @@
+package cache
+
+import (
+	"context"
+	"testing"
+)
+
+// TestGetInt64ReadsBackCounter ensures a counter maintained with Incr/Decr
+// can be read back, since GetInt asserts int while Incr/Decr store int64.
+func TestGetInt64ReadsBackCounter(t *testing.T) {
+	c := New(&Config{Driver: "memory"})
+	ctx := context.Background()
+
+	if _, err := c.Incr(ctx, "hits", 5); err != nil {
+		t.Fatalf("Incr: %v", err)
+	}
+
+	if _, ok, err := c.GetInt(ctx, "hits"); err != nil || ok {
+		t.Fatalf("GetInt unexpectedly matched an int64 counter: ok=%v err=%v", ok, err)
+	}
+
+	n, ok, err := c.GetInt64(ctx, "hits")
+	if err != nil {
+		t.Fatalf("GetInt64: %v", err)
+	}
+	if !ok {
+		t.Fatal("GetInt64 did not find the counter")
+	}
+	if n != 5 {
+		t.Fatalf("expected 5, got %d", n)
+	}
+}
@@ -0,0 +1,35 @@
+package cache
+
+import "fmt"
+
+// DriverError wraps a failure returned by a Driver implementation with the
+// operation and key that triggered it, so callers and logs can tell a
+// network/backend failure apart from a plain cache miss.
+type DriverError struct {
+	Op  string
+	Key string
+	Err error
+}
+
+func (e *DriverError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("cache: %s: %v", e.Op, e.Err)
+	}
+
+	return fmt.Sprintf("cache: %s %q: %v", e.Op, e.Key, e.Err)
+}
+
+func (e *DriverError) Unwrap() error {
+	return e.Err
+}
+
+// PanicError reports that a Remember/RememberContext callback panicked.
+// It is surfaced to every caller waiting on that key so a panic in the
+// callback never looks like a cached nil was legitimately returned.
+type PanicError struct {
+	Value interface{}
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("cache: callback panicked: %v", e.Value)
+}
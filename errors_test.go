@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDriverErrorFormatsWithAndWithoutKey(t *testing.T) {
+	wrapped := errors.New("boom")
+
+	withKey := &DriverError{Op: "get", Key: "k", Err: wrapped}
+	if got, want := withKey.Error(), `cache: get "k": boom`; got != want {
+		t.Fatalf("Error() = %q, want %q", got, want)
+	}
+
+	withoutKey := &DriverError{Op: "clear", Err: wrapped}
+	if got, want := withoutKey.Error(), "cache: clear: boom"; got != want {
+		t.Fatalf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestDriverErrorUnwrap(t *testing.T) {
+	wrapped := errors.New("boom")
+	de := &DriverError{Op: "get", Key: "k", Err: wrapped}
+
+	if !errors.Is(de, wrapped) {
+		t.Fatal("expected errors.Is to see through DriverError to the wrapped error")
+	}
+}
+
+func TestPanicErrorFormatsRecoveredValue(t *testing.T) {
+	pe := &PanicError{Value: "boom"}
+	if got, want := pe.Error(), "cache: callback panicked: boom"; got != want {
+		t.Fatalf("Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,63 @@
+package cache
+
+// Op identifies the kind of mutation an Event describes
+type Op int
+
+const (
+	// OpPut is emitted when a key is added or overwritten
+	OpPut Op = iota
+	// OpRemove is emitted when a key is deleted
+	OpRemove
+	// OpClear is emitted when the whole cache is emptied
+	OpClear
+)
+
+func (o Op) String() string {
+	switch o {
+	case OpPut:
+		return "put"
+	case OpRemove:
+		return "remove"
+	case OpClear:
+		return "clear"
+	default:
+		return "unknown"
+	}
+}
+
+// Event describes a single mutation made through the Cache facade, or a key
+// the driver dropped on its own (reported as OpRemove).
+// Key is empty for OpClear, which affects every key at once
+type Event struct {
+	Op  Op
+	Key string
+}
+
+// subscriberBuffer is the channel capacity given to every Subscribe call
+const subscriberBuffer = 16
+
+// Subscribe returns a channel that receives an Event for every Put, Remove
+// and Clear made through this Cache. The channel is buffered; if a subscriber
+// falls behind, further events are dropped for it rather than blocking callers
+func (c *Cache) Subscribe() <-chan Event {
+	ch := make(chan Event, subscriberBuffer)
+
+	c.subsMu.Lock()
+	c.subs = append(c.subs, ch)
+	c.subsMu.Unlock()
+
+	return ch
+}
+
+// emit broadcasts ev to every subscriber without blocking
+func (c *Cache) emit(ev Event) {
+	c.subsMu.Lock()
+	defer c.subsMu.Unlock()
+
+	for _, ch := range c.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestIncrDecrEmitEvents ensures subscribers see counter mutations made
+// through Incr/Decr, not just Put/Remove/Clear.
+func TestIncrDecrEmitEvents(t *testing.T) {
+	c := New(&Config{Driver: "memory"})
+	ctx := context.Background()
+
+	ch := c.Subscribe()
+
+	if _, err := c.Incr(ctx, "counter", 1); err != nil {
+		t.Fatalf("Incr: %v", err)
+	}
+
+	select {
+	case ev := <-ch:
+		if ev.Op != OpPut || ev.Key != "counter" {
+			t.Fatalf("unexpected event for Incr: %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no event received for Incr")
+	}
+
+	if _, err := c.Decr(ctx, "counter", 1); err != nil {
+		t.Fatalf("Decr: %v", err)
+	}
+
+	select {
+	case ev := <-ch:
+		if ev.Op != OpPut || ev.Key != "counter" {
+			t.Fatalf("unexpected event for Decr: %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no event received for Decr")
+	}
+}
@@ -0,0 +1,210 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+type (
+	// LRUCacheItem represents an item to be put in the LRU cache
+	LRUCacheItem struct {
+		key       string
+		value     interface{}
+		expiresAt int64
+	}
+
+	// LRUCache represents a size-bounded, least-recently-used cache driver instance
+	LRUCache struct {
+		sync.Mutex
+		maxSize int
+		list    *list.List
+		store   map[string]*list.Element
+		evict   func(key string)
+	}
+)
+
+func init() {
+	RegisterDriver("lru", func(cfg *Config) Driver { return NewLRUCache(cfg.MaxSize) })
+}
+
+// NewLRUCache creates and returns an LRU cache driver instance that evicts
+// the least recently used item once more than maxSize items are stored.
+// A maxSize of less than 1 leaves the cache unbounded.
+func NewLRUCache(maxSize int) Driver {
+	return &LRUCache{
+		maxSize: maxSize,
+		list:    list.New(),
+		store:   make(map[string]*list.Element),
+	}
+}
+
+// OnEvict registers fn to be invoked whenever the cache drops a key on its
+// own initiative - size-bounded eviction, or lazily discovering an expired
+// entry on Get - rather than through an explicit Remove or Clear
+func (l *LRUCache) OnEvict(fn func(key string)) {
+	l.Lock()
+	l.evict = fn
+	l.Unlock()
+}
+
+// notifyEvict invokes the registered eviction callback, if any
+// callers must hold the lock
+func (l *LRUCache) notifyEvict(key string) {
+	if l.evict != nil {
+		l.evict(key)
+	}
+}
+
+// Get fetches an item from the cache, moving it to the front as most recently used
+// returns the item and a boolean indicating whether the item was found
+// false if not found or expired, true if found
+func (l *LRUCache) Get(ctx context.Context, key string) (interface{}, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	el, ok := l.store[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	item := el.Value.(*LRUCacheItem)
+	if item.expiresAt > 0 && item.expiresAt < time.Now().Unix() {
+		l.removeElement(el)
+		l.notifyEvict(item.key)
+		return nil, false, nil
+	}
+
+	l.list.MoveToFront(el)
+	return item.value, true, nil
+}
+
+// Put puts an item into the cache for the specified duration in seconds,
+// evicting the least recently used item if the cache grows past maxSize.
+// An expiration of less than 1 leaves the item in cache forever
+func (l *LRUCache) Put(ctx context.Context, key string, data interface{}, duration int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var expiresAt int64
+	if duration > 0 {
+		expiresAt = time.Now().Unix() + duration
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	if el, ok := l.store[key]; ok {
+		el.Value.(*LRUCacheItem).value = data
+		el.Value.(*LRUCacheItem).expiresAt = expiresAt
+		l.list.MoveToFront(el)
+		return nil
+	}
+
+	el := l.list.PushFront(&LRUCacheItem{key: key, value: data, expiresAt: expiresAt})
+	l.store[key] = el
+
+	if l.maxSize > 0 && l.list.Len() > l.maxSize {
+		back := l.list.Back()
+		evictedKey := back.Value.(*LRUCacheItem).key
+		l.removeElement(back)
+		l.notifyEvict(evictedKey)
+	}
+
+	return nil
+}
+
+// Remove removes an item from the cache
+func (l *LRUCache) Remove(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	if el, ok := l.store[key]; ok {
+		l.removeElement(el)
+	}
+
+	return nil
+}
+
+// Incr atomically adds delta to the integer stored at key, creating it
+// with a value of 0 first if it doesn't already exist, and marks it most recently used
+func (l *LRUCache) Incr(ctx context.Context, key string, delta int64) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	el, ok := l.store[key]
+	if ok {
+		item := el.Value.(*LRUCacheItem)
+		if item.expiresAt > 0 && item.expiresAt < time.Now().Unix() {
+			l.removeElement(el)
+			ok = false
+		}
+	}
+
+	if !ok {
+		el = l.list.PushFront(&LRUCacheItem{key: key, value: int64(0)})
+		l.store[key] = el
+
+		if l.maxSize > 0 && l.list.Len() > l.maxSize {
+			back := l.list.Back()
+			evictedKey := back.Value.(*LRUCacheItem).key
+			l.removeElement(back)
+			l.notifyEvict(evictedKey)
+		}
+	}
+
+	item := el.Value.(*LRUCacheItem)
+	n, ok := item.value.(int64)
+	if !ok {
+		return 0, &DriverError{Op: "incr", Key: key, Err: errors.New("value is not an int64")}
+	}
+
+	n += delta
+	item.value = n
+	l.list.MoveToFront(el)
+
+	return n, nil
+}
+
+// Decr atomically subtracts delta from the integer stored at key, creating it
+// with a value of 0 first if it doesn't already exist, and marks it most recently used
+func (l *LRUCache) Decr(ctx context.Context, key string, delta int64) (int64, error) {
+	return l.Incr(ctx, key, -delta)
+}
+
+// Clear empties the cache
+func (l *LRUCache) Clear(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	l.list = list.New()
+	l.store = make(map[string]*list.Element)
+
+	return nil
+}
+
+// removeElement detaches el from both the list and the store
+// callers must hold the lock
+func (l *LRUCache) removeElement(el *list.Element) {
+	l.list.Remove(el)
+	delete(l.store, el.Value.(*LRUCacheItem).key)
+}
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"testing"
+)
+
+// TestLRUCacheEvictsLeastRecentlyUsed ensures the driver evicts the least
+// recently used entry once more than MaxSize items are stored, and that
+// touching an entry via Get protects it from eviction.
+func TestLRUCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	l := NewLRUCache(2)
+	ctx := context.Background()
+
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	must(l.Put(ctx, "a", 1, 0))
+	must(l.Put(ctx, "b", 2, 0))
+
+	// touch "a" so "b" becomes the least recently used
+	if _, ok, err := l.Get(ctx, "a"); err != nil || !ok {
+		t.Fatalf("Get a: ok=%v err=%v", ok, err)
+	}
+
+	must(l.Put(ctx, "c", 3, 0))
+
+	if _, ok, _ := l.Get(ctx, "b"); ok {
+		t.Fatal("expected b to be evicted as least recently used")
+	}
+	if _, ok, _ := l.Get(ctx, "a"); !ok {
+		t.Fatal("expected a to survive eviction, it was touched most recently")
+	}
+	if _, ok, _ := l.Get(ctx, "c"); !ok {
+		t.Fatal("expected c to be present, it was just added")
+	}
+}
+
+// TestLRUCacheUnbounded ensures a MaxSize below 1 leaves the cache unbounded
+func TestLRUCacheUnbounded(t *testing.T) {
+	l := NewLRUCache(0)
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		if err := l.Put(ctx, string(rune('a'+i)), i, 0); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		if _, ok, _ := l.Get(ctx, string(rune('a'+i))); !ok {
+			t.Fatalf("expected key %c to still be present", 'a'+i)
+		}
+	}
+}
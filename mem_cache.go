@@ -1,6 +1,8 @@
 package cache
 
 import (
+	"context"
+	"errors"
 	"sync"
 	"time"
 )
@@ -15,31 +17,112 @@ type (
 	// MemoryCache represents a memory cache driver instance
 	MemoryCache struct {
 		sync.RWMutex
-		store map[string]*MemoryCacheItem
+		store     map[string]*MemoryCacheItem
+		done      chan struct{}
+		closeOnce sync.Once
+		evict     func(key string)
 	}
 )
 
-// NewMemoryCache creates and returns a memory cache driver instance
-func NewMemoryCache() Driver {
-	return &MemoryCache{
+// NewMemoryCache creates and returns a memory cache driver instance.
+// When gcInterval is greater than zero, a janitor goroutine periodically
+// evicts expired items; call Close to stop it
+func NewMemoryCache(gcInterval time.Duration) Driver {
+	m := &MemoryCache{
 		store: make(map[string]*MemoryCacheItem),
+		done:  make(chan struct{}),
+	}
+
+	if gcInterval > 0 {
+		go m.janitor(gcInterval)
+	}
+
+	return m
+}
+
+// janitor periodically evicts expired items until Close is called
+func (m *MemoryCache) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.gc()
+		case <-m.done:
+			return
+		}
+	}
+}
+
+// gc removes every item whose expiresAt has passed
+func (m *MemoryCache) gc() {
+	now := time.Now().Unix()
+
+	m.Lock()
+	var evicted []string
+	for key, item := range m.store {
+		if item.expiresAt > 0 && item.expiresAt < now {
+			delete(m.store, key)
+			evicted = append(evicted, key)
+		}
+	}
+	evict := m.evict
+	m.Unlock()
+
+	if evict == nil {
+		return
+	}
+
+	for _, key := range evicted {
+		evict(key)
 	}
 }
 
+// OnEvict registers fn to be invoked whenever the janitor drops an expired
+// key on its own initiative, rather than through an explicit Remove or Clear
+func (m *MemoryCache) OnEvict(fn func(key string)) {
+	m.Lock()
+	m.evict = fn
+	m.Unlock()
+}
+
+// Close stops the janitor goroutine. Safe to call more than once
+func (m *MemoryCache) Close() error {
+	m.closeOnce.Do(func() { close(m.done) })
+	return nil
+}
+
 // Get fetches an item from the cache
 // returns the item and a boolean indicating whether the item was found
-// false if not found, true if found
-func (m *MemoryCache) Get(key string) (interface{}, bool) {
-	if data := m.store[key]; data != nil {
-		return data.value, true
+// false if not found or expired, true if found
+func (m *MemoryCache) Get(ctx context.Context, key string) (interface{}, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+
+	m.RLock()
+	data := m.store[key]
+	m.RUnlock()
+
+	if data == nil {
+		return nil, false, nil
 	}
 
-	return nil, false
+	if data.expiresAt > 0 && data.expiresAt < time.Now().Unix() {
+		return nil, false, nil
+	}
+
+	return data.value, true, nil
 }
 
 // Put puts an item into the cache for the specified duration in seconds
 // An expiration of less than 1 leaves the item in cache forever
-func (m *MemoryCache) Put(key string, data interface{}, duration int64) {
+func (m *MemoryCache) Put(ctx context.Context, key string, data interface{}, duration int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	d := &MemoryCacheItem{
 		value: data,
 	}
@@ -53,18 +136,69 @@ func (m *MemoryCache) Put(key string, data interface{}, duration int64) {
 	m.Lock()
 	m.store[key] = d
 	m.Unlock()
+
+	return nil
 }
 
 // Remove removes an item from the cache
-func (m *MemoryCache) Remove(key string) {
+func (m *MemoryCache) Remove(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	m.Lock()
 	delete(m.store, key)
 	m.Unlock()
+
+	return nil
+}
+
+// Incr atomically adds delta to the integer stored at key, creating it
+// with a value of 0 first if it doesn't already exist, preserving its expiry
+func (m *MemoryCache) Incr(ctx context.Context, key string, delta int64) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	item, ok := m.store[key]
+	if ok && item.expiresAt > 0 && item.expiresAt < time.Now().Unix() {
+		ok = false
+	}
+
+	if !ok {
+		item = &MemoryCacheItem{value: int64(0)}
+		m.store[key] = item
+	}
+
+	n, ok := item.value.(int64)
+	if !ok {
+		return 0, &DriverError{Op: "incr", Key: key, Err: errors.New("value is not an int64")}
+	}
+
+	n += delta
+	item.value = n
+
+	return n, nil
+}
+
+// Decr atomically subtracts delta from the integer stored at key, creating it
+// with a value of 0 first if it doesn't already exist, preserving its expiry
+func (m *MemoryCache) Decr(ctx context.Context, key string, delta int64) (int64, error) {
+	return m.Incr(ctx, key, -delta)
 }
 
 // Clear empties the cache
-func (m *MemoryCache) Clear() {
+func (m *MemoryCache) Clear(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	m.Lock()
 	m.store = make(map[string]*MemoryCacheItem)
 	m.Unlock()
+
+	return nil
 }
@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestMemoryCacheJanitorEvictsExpiredItems ensures the background janitor
+// removes expired items on its own, without a Get ever touching the key.
+func TestMemoryCacheJanitorEvictsExpiredItems(t *testing.T) {
+	m := NewMemoryCache(50 * time.Millisecond)
+	defer m.(*MemoryCache).Close()
+	ctx := context.Background()
+
+	if err := m.Put(ctx, "a", "v", 1); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		m.(*MemoryCache).RLock()
+		_, present := m.(*MemoryCache).store["a"]
+		m.(*MemoryCache).RUnlock()
+		if !present {
+			return
+		}
+		time.Sleep(25 * time.Millisecond)
+	}
+
+	t.Fatal("janitor never evicted the expired item from the store")
+}
+
+// TestMemoryCacheCloseStopsJanitor ensures Close stops the janitor goroutine
+// and is safe to call more than once.
+func TestMemoryCacheCloseStopsJanitor(t *testing.T) {
+	m := NewMemoryCache(10 * time.Millisecond).(*MemoryCache)
+
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+}
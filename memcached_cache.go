@@ -0,0 +1,163 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"strconv"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+type (
+	// MemcachedCache represents a memcached-backed cache driver instance
+	MemcachedCache struct {
+		client *memcache.Client
+	}
+)
+
+func init() {
+	RegisterDriver("memcached", NewMemcachedCache)
+}
+
+// NewMemcachedCache creates and returns a memcached cache driver instance,
+// dialing cfg.Address with an idle connection pool sized by cfg.PoolSize.
+// The underlying memcached protocol has no concept of Username/Password,
+// so those fields are ignored by this driver.
+func NewMemcachedCache(cfg *Config) Driver {
+	client := memcache.New(cfg.Address)
+	if cfg.PoolSize > 0 {
+		client.MaxIdleConns = cfg.PoolSize
+	}
+
+	return &MemcachedCache{client: client}
+}
+
+// Get fetches an item from the cache
+// returns the item and a boolean indicating whether the item was found
+// false if not found, true if found
+func (m *MemcachedCache) Get(ctx context.Context, key string) (interface{}, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+
+	item, err := m.client.Get(key)
+	if err == memcache.ErrCacheMiss {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, &DriverError{Op: "get", Key: key, Err: err}
+	}
+
+	var data interface{}
+	if err := gob.NewDecoder(bytes.NewReader(item.Value)).Decode(&data); err != nil {
+		// Incr/Decr write their result via the native increment/decrement
+		// command, which stores a plain decimal string rather than a
+		// gob-encoded value - fall back to parsing it as the int64 a
+		// counter holds
+		if n, perr := strconv.ParseInt(string(item.Value), 10, 64); perr == nil {
+			return n, true, nil
+		}
+
+		return nil, false, &DriverError{Op: "get", Key: key, Err: err}
+	}
+
+	return data, true, nil
+}
+
+// Put puts an item into the cache for the specified duration in seconds
+// An expiration of less than 1 leaves the item in cache forever
+func (m *MemcachedCache) Put(ctx context.Context, key string, data interface{}, duration int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&data); err != nil {
+		return &DriverError{Op: "put", Key: key, Err: err}
+	}
+
+	if duration < 1 {
+		duration = 0
+	}
+
+	err := m.client.Set(&memcache.Item{
+		Key:        key,
+		Value:      buf.Bytes(),
+		Expiration: int32(duration),
+	})
+	if err != nil {
+		return &DriverError{Op: "put", Key: key, Err: err}
+	}
+
+	return nil
+}
+
+// Remove removes an item from the cache
+func (m *MemcachedCache) Remove(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := m.client.Delete(key); err != nil && err != memcache.ErrCacheMiss {
+		return &DriverError{Op: "remove", Key: key, Err: err}
+	}
+
+	return nil
+}
+
+// Clear empties the cache
+func (m *MemcachedCache) Clear(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := m.client.FlushAll(); err != nil {
+		return &DriverError{Op: "clear", Err: err}
+	}
+
+	return nil
+}
+
+// Incr atomically adds delta to the integer stored at key via the native
+// increment command, creating it with a value of 0 first if it doesn't already exist.
+// A negative delta goes through the native decrement command; memcached
+// counters are unsigned, so a result below 0 is stored and returned as 0
+func (m *MemcachedCache) Incr(ctx context.Context, key string, delta int64) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	n, err := m.nativeDelta(key, delta)
+	if err == memcache.ErrCacheMiss {
+		if addErr := m.client.Add(&memcache.Item{Key: key, Value: []byte("0")}); addErr != nil && addErr != memcache.ErrNotStored {
+			return 0, &DriverError{Op: "incr", Key: key, Err: addErr}
+		}
+
+		n, err = m.nativeDelta(key, delta)
+	}
+	if err != nil {
+		return 0, &DriverError{Op: "incr", Key: key, Err: err}
+	}
+
+	return int64(n), nil
+}
+
+// nativeDelta dispatches to memcached's native increment/decrement command
+// based on the sign of delta. The wire protocol only accepts a uint64
+// magnitude, so a negative delta has to go through Decrement rather than
+// wrapping into a huge Increment argument
+func (m *MemcachedCache) nativeDelta(key string, delta int64) (uint64, error) {
+	if delta < 0 {
+		return m.client.Decrement(key, uint64(-delta))
+	}
+
+	return m.client.Increment(key, uint64(delta))
+}
+
+// Decr atomically subtracts delta from the integer stored at key, creating it
+// with a value of 0 first if it doesn't already exist. The result does not go
+// below 0, as with Incr and a negative delta
+func (m *MemcachedCache) Decr(ctx context.Context, key string, delta int64) (int64, error) {
+	return m.Incr(ctx, key, -delta)
+}
@@ -0,0 +1,59 @@
+package cache
+
+import "sync"
+
+// call represents an in-flight or completed Remember callback invocation
+// shared between callers racing on the same key
+type call struct {
+	wg    sync.WaitGroup
+	value interface{}
+	err   error
+}
+
+// callGroup deduplicates concurrent Remember callback invocations for the
+// same key so that only one of them actually runs, similar to
+// golang.org/x/sync/singleflight
+type callGroup struct {
+	sync.Mutex
+	calls map[string]*call
+}
+
+// do runs fn for key if no call is already in flight for it, otherwise it
+// waits for the in-flight call and returns its result
+func (g *callGroup) do(key string, fn func() (interface{}, error)) (interface{}, error) {
+	g.Lock()
+	if c, ok := g.calls[key]; ok {
+		g.Unlock()
+		c.wg.Wait()
+		return c.value, c.err
+	}
+
+	c := new(call)
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.Unlock()
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				c.err = &PanicError{Value: r}
+			}
+
+			g.Lock()
+			delete(g.calls, key)
+			g.Unlock()
+
+			c.wg.Done()
+
+			// re-panic in the leader's own goroutine now that waiters have
+			// been released with a real error, rather than swallowing it
+			if pe, ok := c.err.(*PanicError); ok {
+				panic(pe)
+			}
+		}()
+
+		c.value, c.err = fn()
+	}()
+
+	return c.value, c.err
+}
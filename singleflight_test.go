@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestCallGroupPanicReleasesWaiters ensures a panicking leader callback
+// still releases waiters blocked on c.wg.Wait(), hands them a real
+// *PanicError instead of a fabricated (nil, nil) success, and still lets
+// the panic surface in the leader's own goroutine.
+func TestCallGroupPanicReleasesWaiters(t *testing.T) {
+	g := &callGroup{calls: make(map[string]*call)}
+
+	leaderStarted := make(chan struct{})
+	release := make(chan struct{})
+	leaderDone := make(chan struct{})
+
+	var leaderPanic interface{}
+	go func() {
+		defer close(leaderDone)
+		defer func() { leaderPanic = recover() }()
+
+		g.do("k", func() (interface{}, error) {
+			close(leaderStarted)
+			<-release
+			panic("boom")
+		})
+	}()
+
+	<-leaderStarted
+
+	waiterDone := make(chan struct{})
+	var waiterValue interface{}
+	var waiterErr error
+	go func() {
+		defer close(waiterDone)
+		waiterValue, waiterErr = g.do("k", func() (interface{}, error) {
+			t.Error("waiter must share the leader's call, not run its own callback")
+			return nil, nil
+		})
+	}()
+
+	// give the waiter goroutine time to reach g.do and join the in-flight
+	// call before the leader's callback is allowed to panic
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	select {
+	case <-waiterDone:
+	case <-time.After(2 * time.Second):
+		t.Fatal("waiter blocked forever after the leader's callback panicked")
+	}
+	<-leaderDone
+
+	if leaderPanic == nil {
+		t.Fatal("expected the leader goroutine to still observe the panic")
+	}
+
+	if waiterValue != nil {
+		t.Fatalf("expected waiter to get a nil value, got %v", waiterValue)
+	}
+	if waiterErr == nil {
+		t.Fatal("expected waiter to get a non-nil error instead of silent (nil, nil) success")
+	}
+	if _, ok := waiterErr.(*PanicError); !ok {
+		t.Fatalf("expected a *PanicError, got %T: %v", waiterErr, waiterErr)
+	}
+}
+
+// TestCallGroupDeduplicates ensures concurrent calls for the same key only
+// run the callback once and share its result.
+func TestCallGroupDeduplicates(t *testing.T) {
+	g := &callGroup{calls: make(map[string]*call)}
+
+	var calls int32
+	var mu sync.Mutex
+	start := make(chan struct{})
+
+	var wg sync.WaitGroup
+	results := make([]interface{}, 4)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			v, _ := g.do("k", func() (interface{}, error) {
+				mu.Lock()
+				calls++
+				mu.Unlock()
+				time.Sleep(10 * time.Millisecond)
+				return "v", nil
+			})
+			results[i] = v
+		}(i)
+	}
+
+	close(start)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("expected callback to run once, ran %d times", calls)
+	}
+	for _, r := range results {
+		if r != "v" {
+			t.Fatalf("expected every caller to see shared result, got %v", r)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package cache
+
+import "context"
+
+// PutTagged does the same as Put, additionally recording key against every
+// given tag so it can later be wiped in bulk with InvalidateTag. Re-tagging a
+// key drops its previous tag associations
+func (c *Cache) PutTagged(ctx context.Context, key string, data interface{}, duration int64, tags ...string) error {
+	if err := c.Put(ctx, key, data, duration); err != nil {
+		return err
+	}
+
+	c.tagsMu.Lock()
+	defer c.tagsMu.Unlock()
+
+	c.untagLocked(key)
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	keyTagSet := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		if c.tags[tag] == nil {
+			c.tags[tag] = make(map[string]struct{})
+		}
+
+		c.tags[tag][key] = struct{}{}
+		keyTagSet[tag] = struct{}{}
+	}
+	c.keyTags[key] = keyTagSet
+
+	return nil
+}
+
+// InvalidateTag removes every key currently carrying tag. Keys are dropped
+// from the tag index one at a time as their Remove succeeds (via untag), so
+// a failure partway through - a canceled context, a driver error - leaves
+// the remaining keys still tagged and retryable instead of orphaning them
+func (c *Cache) InvalidateTag(ctx context.Context, tag string) error {
+	c.tagsMu.Lock()
+	keys := make([]string, 0, len(c.tags[tag]))
+	for key := range c.tags[tag] {
+		keys = append(keys, key)
+	}
+	c.tagsMu.Unlock()
+
+	for _, key := range keys {
+		if err := c.Remove(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// untag drops key from the tag reverse index. It is called by Remove (and so
+// by Pull and InvalidateTag) and when the driver evicts a key on its own;
+// Clear resets the whole index instead
+func (c *Cache) untag(key string) {
+	c.tagsMu.Lock()
+	defer c.tagsMu.Unlock()
+
+	c.untagLocked(key)
+}
+
+// untagLocked is untag's body; callers must hold tagsMu
+func (c *Cache) untagLocked(key string) {
+	for tag := range c.keyTags[key] {
+		delete(c.tags[tag], key)
+		if len(c.tags[tag]) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+
+	delete(c.keyTags, key)
+}
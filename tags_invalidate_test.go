@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"context"
+	"testing"
+)
+
+// TestInvalidateTagSurvivesPartialFailure ensures a failed Remove partway
+// through InvalidateTag doesn't orphan the remaining keys from the tag
+// index - they must stay tagged and retryable.
+func TestInvalidateTagSurvivesPartialFailure(t *testing.T) {
+	c := New(&Config{Driver: "memory"})
+
+	ctx := context.Background()
+	if err := c.PutTagged(ctx, "a", "1", 0, "g"); err != nil {
+		t.Fatalf("PutTagged a: %v", err)
+	}
+	if err := c.PutTagged(ctx, "b", "2", 0, "g"); err != nil {
+		t.Fatalf("PutTagged b: %v", err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	if err := c.InvalidateTag(canceled, "g"); err == nil {
+		t.Fatal("expected InvalidateTag to fail with a canceled context")
+	}
+
+	c.tagsMu.Lock()
+	remaining := len(c.tags["g"])
+	c.tagsMu.Unlock()
+	if remaining != 2 {
+		t.Fatalf("expected both keys to remain tagged after a failed attempt, got %d", remaining)
+	}
+
+	if err := c.InvalidateTag(ctx, "g"); err != nil {
+		t.Fatalf("retry InvalidateTag: %v", err)
+	}
+
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Fatal("expected a to be removed after retry")
+	}
+	if _, ok, _ := c.Get(ctx, "b"); ok {
+		t.Fatal("expected b to be removed after retry")
+	}
+
+	c.tagsMu.Lock()
+	remaining = len(c.tags["g"])
+	c.tagsMu.Unlock()
+	if remaining != 0 {
+		t.Fatalf("expected tag index to be empty after successful retry, got %d", remaining)
+	}
+}
@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestTagReconciliationOnLRUEviction ensures that when the LRU driver
+// evicts an entry on its own (size-bounded eviction), the tag reverse
+// index is cleaned up too, instead of accumulating entries forever.
+func TestTagReconciliationOnLRUEviction(t *testing.T) {
+	c := New(&Config{Driver: "lru", MaxSize: 1})
+	ctx := context.Background()
+
+	if err := c.PutTagged(ctx, "a", "1", 0, "group"); err != nil {
+		t.Fatalf("PutTagged a: %v", err)
+	}
+	if err := c.PutTagged(ctx, "b", "2", 0, "group"); err != nil {
+		t.Fatalf("PutTagged b: %v", err)
+	}
+
+	c.tagsMu.Lock()
+	_, stillTaggedA := c.keyTags["a"]
+	taggedKeys := len(c.tags["group"])
+	c.tagsMu.Unlock()
+
+	if stillTaggedA {
+		t.Fatal("expected evicted key a to be dropped from keyTags")
+	}
+	if taggedKeys != 1 {
+		t.Fatalf("expected exactly 1 key left under tag \"group\", got %d", taggedKeys)
+	}
+}
+
+// TestTagReconciliationOnJanitorExpiry ensures that when the memory
+// driver's janitor expires an entry in the background, the tag reverse
+// index is cleaned up too.
+func TestTagReconciliationOnJanitorExpiry(t *testing.T) {
+	c := New(&Config{Driver: "memory", GCInterval: 1})
+	defer c.Close()
+	ctx := context.Background()
+
+	if err := c.PutTagged(ctx, "a", "1", 1, "group"); err != nil {
+		t.Fatalf("PutTagged a: %v", err)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		c.tagsMu.Lock()
+		_, tagged := c.keyTags["a"]
+		c.tagsMu.Unlock()
+		if !tagged {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	t.Fatal("expired key was never reconciled out of the tag index")
+}